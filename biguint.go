@@ -3,19 +3,31 @@ package biguint
 import (
 	"errors"
 	"fmt"
+	"io"
+	"math/bits"
 )
 
-// BigUInt type definition, containing a slice of unsigned bytes
-// unsigned ints should be split up into 2 digit base 16 chunks,
-// indexed from least to most significant, e.g:
-// []uint8{ 0x00, 0xff } <=> 0xff00
+// BigUInt type definition. The number is stored as a slice of machine
+// words, indexed from least to most significant (following the design of
+// the standard library's math/big "nat" type), e.g. on a 64-bit platform:
+// []Word{ 0xff00 } <=> 0xff00
 //
-// this is also an example of slice syntax, which are
-// discussed in more detail here https://blog.golang.org/slices-intro
+// see Bytes and Words for ways to project this representation into a
+// plain byte slice or the native word slice, respectively.
 type BigUInt struct {
-	data []uint8
+	data []Word
 }
 
+// Word is a single machine word of a BigUInt, sized to match uintptr so
+// that arithmetic on it maps directly onto the CPU's native word size.
+type Word uintptr
+
+// WordBits is the number of bits in a Word.
+const WordBits = bits.UintSize
+
+// wordBytes is the number of bytes in a Word.
+const wordBytes = WordBits / 8
+
 // ErrUnderflow is the underflow error for subtraction. See https://blog.golang.org/go1.13-errors
 // for an up-to-date discussion of how to define and/or deal with errors.
 //
@@ -38,48 +50,71 @@ func bytesFromUInt64(src uint64) []uint8 {
 	return res
 }
 
+// wordsFromBytes packs a little-endian byte slice into a little-endian
+// slice of Words, wordBytes bytes at a time, dropping trailing (i.e.
+// most significant) zero bytes first.
+func wordsFromBytes(data []uint8) []Word {
+	trimmed := trimZeroes(data)
+	words := make([]Word, (len(trimmed)+wordBytes-1)/wordBytes)
+	for i, b := range trimmed {
+		words[i/wordBytes] |= Word(b) << uint((i%wordBytes)*8)
+	}
+	return words
+}
+
 // NewBigUInt is the constructor for a BigUInt, based on a uint64.
 // This function relies on bytesFromUInt64.
 func NewBigUInt(i uint64) *BigUInt {
-	return &BigUInt{data: bytesFromUInt64(i)}
+	return NewBigUIntFromBytes(bytesFromUInt64(i))
+}
+
+// NewBigUIntFromBytes is the constructor for a BigUInt based on a
+// little-endian byte slice, following the same convention as Bytes. It
+// is provided so that callers built against the original byte-slice
+// representation of BigUInt can still construct values directly from raw
+// bytes.
+func NewBigUIntFromBytes(data []uint8) *BigUInt {
+	return &BigUInt{data: wordsFromBytes(data)}
+}
+
+// addWW adds two words x and y along with a carry-in c (0 or 1),
+// returning the sum and a carry-out (0 or 1). This is the word-wide
+// analog of a full adder, equivalent to math/big's addWW.
+func addWW(x, y, c Word) (sum, carry Word) {
+	sum = x + y + c
+	carry = ((x & y) | ((x | y) &^ sum)) >> (WordBits - 1)
+	return
+}
+
+// subWW subtracts y and a borrow-in b (0 or 1) from x, returning the
+// difference and a borrow-out (0 or 1). This is the word-wide analog of
+// a full subtractor, equivalent to math/big's subWW.
+func subWW(x, y, b Word) (diff, borrow Word) {
+	diff = x - y - b
+	borrow = ((^x & y) | (^(x ^ y) & diff)) >> (WordBits - 1)
+	return
 }
 
 // Add method for BigUInt.
 //
 // Increases x by the number represented by y, returning x.
 // Note that x's slice's size may increase as a result of this operation.
-// TODO
 func (x *BigUInt) Add(y *BigUInt) *BigUInt {
-	var carry uint16
-	var result uint16
 	if len(x.data) < len(y.data) {
 		tmp := x
 		x = y.Copy()
 		y = tmp
 	}
-	i := 0
-	for i < len(x.data) {
-		if i < len(y.data)  {
-			result = uint16(x.data[i]) + uint16(y.data[i]) + carry
-			//fmt.Printf("Adding bytes x=%d + y=%d + carry=%d\n", uint16(x.data[i]), uint16(y.data[i]), carry)
-			carry = result / 256
-			result = result % 256
-			//fmt.Printf("Got result %d, carry %d\n", result, carry)
-			x.data[i] = uint8(result)
-		} else {
-			if carry > 0 {
-				result = uint16(x.data[i]) + carry
-				carry = result / 256
-				result = result % 256
-				x.data[i] = uint8(result)
-			}
+	var carry Word
+	for i := range x.data {
+		var yWord Word
+		if i < len(y.data) {
+			yWord = y.data[i]
 		}
-		result = 0
-		i++
+		x.data[i], carry = addWW(x.data[i], yWord, carry)
 	}
 	if carry > 0 {
-		//fmt.Printf("Adding carry at the front -> %d\n", carry)
-		x.data = append(x.data, uint8(carry))
+		x.data = append(x.data, carry)
 	}
 
 	return x
@@ -92,13 +127,432 @@ func (x *BigUInt) Add(y *BigUInt) *BigUInt {
 //
 // If y > x, then (nil, ErrUnderflow) should be returned, and
 // x should be unchanged.
-// TODO
 func (x *BigUInt) Subtract(y *BigUInt) (*BigUInt, error) {
-	return nil, errors.New("not implemented")
+	if compareWords(x.data, y.data) < 0 {
+		return nil, ErrUnderflow
+	}
+	var borrow Word
+	result := make([]Word, len(x.data))
+	for i := range x.data {
+		var yWord Word
+		if i < len(y.data) {
+			yWord = y.data[i]
+		}
+		result[i], borrow = subWW(x.data[i], yWord, borrow)
+	}
+	x.data = trimZeroWords(result)
+	return x, nil
+}
+
+// ErrDivideByZero is returned by Multiply's division counterparts (DivMod,
+// Divide, Modulo) when the divisor is zero.
+var ErrDivideByZero = errors.New("division by zero")
+
+// Multiply method for BigUInt.
+//
+// Multiplies x by the number represented by y, returning x.
+// Note that x's slice's size may increase as a result of this operation.
+//
+// Implemented in terms of multiplyBytes over the projected byte
+// representation of x and y; see multiplyBytes for the algorithm.
+func (x *BigUInt) Multiply(y *BigUInt) *BigUInt {
+	x.data = wordsFromBytes(multiplyBytes(x.Bytes(), y.Bytes()))
+	return x
+}
+
+// multiplyBytes computes the product of two little-endian byte slices
+// using grade-school long multiplication: for every pair of digits a[i],
+// b[j], the partial product is accumulated into result[i+j], with any
+// carry propagated into result[i+j+1] and beyond.
+func multiplyBytes(a, b []uint8) []uint8 {
+	if len(a) == 0 || len(b) == 0 {
+		return []uint8{}
+	}
+	acc := make([]uint16, len(a)+len(b))
+	for i := range a {
+		var carry uint16
+		for j := range b {
+			product := uint16(a[i])*uint16(b[j]) + acc[i+j] + carry
+			acc[i+j] = product % 256
+			carry = product / 256
+		}
+		for k := i + len(b); carry > 0; k++ {
+			sum := acc[k] + carry
+			acc[k] = sum % 256
+			carry = sum / 256
+		}
+	}
+	result := make([]uint8, len(acc))
+	for i, digit := range acc {
+		result[i] = uint8(digit)
+	}
+	return trimZeroes(result)
+}
+
+// DivMod divides x by y, returning the quotient and remainder of the
+// division, leaving both x and y unchanged. If y is zero, (nil, nil,
+// ErrDivideByZero) is returned.
+//
+// Implemented in terms of divModBytes over the projected byte
+// representation of x and y; see divModBytes for the algorithm.
+func (x *BigUInt) DivMod(y *BigUInt) (*BigUInt, *BigUInt, error) {
+	quotientBytes, remainderBytes, err := divModBytes(x.Bytes(), y.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewBigUIntFromBytes(quotientBytes), NewBigUIntFromBytes(remainderBytes), nil
+}
+
+// divModBytes divides the little-endian byte slice a by b, returning the
+// quotient and remainder. If b is zero, (nil, nil, ErrDivideByZero) is
+// returned.
+//
+// Implements schoolbook long division: b is shifted left byte-by-byte
+// until it would exceed the dividend, then at each shift position k the
+// largest byte-valued multiplier m such that m*(b<<k) <= remainder is
+// found via binary search and subtracted out, contributing m to the
+// quotient at position k.
+func divModBytes(a, b []uint8) ([]uint8, []uint8, error) {
+	if len(b) == 0 {
+		return nil, nil, ErrDivideByZero
+	}
+	remainder := append([]uint8{}, a...)
+	if compareBytes(a, b) < 0 {
+		return []uint8{}, remainder, nil
+	}
+	shift := 0
+	for compareBytes(shiftBytes(b, shift+1), remainder) <= 0 {
+		shift++
+	}
+	quotient := make([]uint8, shift+1)
+	for k := shift; k >= 0; k-- {
+		divisor := shiftBytes(b, k)
+		lo, hi, m := 0, 255, 0
+		for lo <= hi {
+			mid := (lo + hi) / 2
+			candidate := multiplyBytes(bytesFromUInt64(uint64(mid)), divisor)
+			if compareBytes(candidate, remainder) <= 0 {
+				m = mid
+				lo = mid + 1
+			} else {
+				hi = mid - 1
+			}
+		}
+		if m > 0 {
+			product := multiplyBytes(bytesFromUInt64(uint64(m)), divisor)
+			var err error
+			remainder, err = subtractBytes(remainder, product)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		quotient[k] = uint8(m)
+	}
+	return trimZeroes(quotient), remainder, nil
+}
+
+// subtractBytes subtracts the little-endian byte slice b from a,
+// returning the (trimmed) difference, or ErrUnderflow if b > a.
+func subtractBytes(a, b []uint8) ([]uint8, error) {
+	if compareBytes(a, b) < 0 {
+		return nil, ErrUnderflow
+	}
+	var borrow int16
+	result := make([]uint8, len(a))
+	for i := range a {
+		var bDigit int16
+		if i < len(b) {
+			bDigit = int16(b[i])
+		}
+		diff := int16(a[i]) - bDigit - borrow
+		if diff < 0 {
+			diff += 256
+			borrow = 1
+		} else {
+			borrow = 0
+		}
+		result[i] = uint8(diff)
+	}
+	return trimZeroes(result), nil
+}
+
+// Divide returns the quotient of x divided by y, leaving x and y
+// unchanged. If y is zero, (nil, ErrDivideByZero) is returned.
+func (x *BigUInt) Divide(y *BigUInt) (*BigUInt, error) {
+	quotient, _, err := x.DivMod(y)
+	if err != nil {
+		return nil, err
+	}
+	return quotient, nil
+}
+
+// Modulo returns the remainder of x divided by y, leaving x and y
+// unchanged. If y is zero, (nil, ErrDivideByZero) is returned.
+func (x *BigUInt) Modulo(y *BigUInt) (*BigUInt, error) {
+	_, remainder, err := x.DivMod(y)
+	if err != nil {
+		return nil, err
+	}
+	return remainder, nil
+}
+
+// significantLen returns the length of data with trailing (i.e. most
+// significant) zero bytes dropped.
+func significantLen(data []uint8) int {
+	n := len(data)
+	for n > 0 && data[n-1] == 0 {
+		n--
+	}
+	return n
+}
+
+// trimZeroes drops trailing (i.e. most significant) zero bytes from data,
+// matching the no-leading-zeroes convention used throughout this package.
+func trimZeroes(data []uint8) []uint8 {
+	return data[:significantLen(data)]
+}
+
+// significantWordLen returns the length of data with trailing (i.e. most
+// significant) zero words dropped.
+func significantWordLen(data []Word) int {
+	n := len(data)
+	for n > 0 && data[n-1] == 0 {
+		n--
+	}
+	return n
+}
+
+// trimZeroWords drops trailing (i.e. most significant) zero words from
+// data, matching the no-leading-zeroes convention used throughout this
+// package.
+func trimZeroWords(data []Word) []Word {
+	return data[:significantWordLen(data)]
+}
+
+// compareBytes compares two little-endian byte slices as unsigned
+// integers, ignoring any trailing zero bytes. It returns -1, 0, or 1 as a
+// is less than, equal to, or greater than b.
+func compareBytes(a, b []uint8) int {
+	la, lb := significantLen(a), significantLen(b)
+	if la != lb {
+		if la < lb {
+			return -1
+		}
+		return 1
+	}
+	for i := la - 1; i >= 0; i-- {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// compareWords compares two little-endian word slices as unsigned
+// integers, ignoring any trailing zero words. It returns -1, 0, or 1 as a
+// is less than, equal to, or greater than b.
+func compareWords(a, b []Word) int {
+	la, lb := significantWordLen(a), significantWordLen(b)
+	if la != lb {
+		if la < lb {
+			return -1
+		}
+		return 1
+	}
+	for i := la - 1; i >= 0; i-- {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// shiftBytes returns data shifted left by k bytes (i.e. multiplied by
+// 256^k), by prepending k zero bytes to the low end of the slice.
+func shiftBytes(data []uint8, k int) []uint8 {
+	if k == 0 {
+		return data
+	}
+	shifted := make([]uint8, k+len(data))
+	copy(shifted[k:], data)
+	return shifted
+}
+
+// ErrVarintOverflow is returned by the varint readers when a byte stream
+// never terminates (i.e. every byte examined has its continuation bit
+// set) within the space available to read from.
+var ErrVarintOverflow = errors.New("varint overflow")
+
+// maxVarintBytes bounds how many continuation bytes ReadVarintFrom will
+// read from a stream before giving up, since an io.ByteReader has no
+// length of its own to bound a malformed, never-terminating stream.
+const maxVarintBytes = 1 << 20
+
+// bitLength returns the number of bits needed to represent data, with
+// trailing (i.e. most significant) zero bytes ignored. bitLength(0) is 0.
+func bitLength(data []uint8) int {
+	n := significantLen(data)
+	if n == 0 {
+		return 0
+	}
+	bits := (n - 1) * 8
+	for top := data[n-1]; top > 0; top >>= 1 {
+		bits++
+	}
+	return bits
+}
+
+// SizeVarint reports the number of bytes PutVarint would use to encode x,
+// without allocating.
+func (x *BigUInt) SizeVarint() int {
+	n := significantWordLen(x.data)
+	if n == 0 {
+		return 1
+	}
+	bitLen := (n-1)*WordBits + bits.Len(uint(x.data[n-1]))
+	return (bitLen + 6) / 7
+}
+
+// PutVarint encodes x into buf using the unsigned LEB128 scheme also used
+// by encoding/binary's Uvarint: little-endian 7-bit groups, with the high
+// bit of every byte but the last set to indicate that more groups follow.
+// Unlike binary.PutUvarint, x is unbounded, so the encoding may be longer
+// than binary.MaxVarintLen64. It returns the number of bytes written to
+// buf, or (0, io.ErrShortBuffer) if buf is too small to hold the result.
+//
+// This makes a single pass over x's bytes, refilling a small bit
+// accumulator as it goes, rather than re-deriving a shifted copy of the
+// (shrinking) value on every 7-bit group - the latter is what the
+// original implementation did, and it made PutVarint quadratic in the
+// size of x.
+func (x *BigUInt) PutVarint(buf []byte) (int, error) {
+	size := x.SizeVarint()
+	if len(buf) < size {
+		return 0, io.ErrShortBuffer
+	}
+	data := x.Bytes()
+	var acc uint32
+	accBits, byteIdx := uint(0), 0
+	for i := 0; i < size; i++ {
+		for accBits < 7 && byteIdx < len(data) {
+			acc |= uint32(data[byteIdx]) << accBits
+			accBits += 8
+			byteIdx++
+		}
+		group := uint8(acc & 0x7F)
+		acc >>= 7
+		if accBits > 7 {
+			accBits -= 7
+		} else {
+			accBits = 0
+		}
+		if i < size-1 {
+			group |= 0x80
+		}
+		buf[i] = group
+	}
+	return size, nil
+}
+
+// ReadVarint decodes a BigUInt from the front of buf, using the scheme
+// described at PutVarint. It returns the number of bytes read from buf,
+// or ErrVarintOverflow if buf is exhausted before a terminating byte (one
+// with its high bit clear) is found.
+//
+// This makes a single pass over buf, accumulating 7-bit groups into a
+// small bit buffer and flushing whole bytes out of it as they fill up,
+// rather than growing the result one BigUInt.Add per group (which made
+// the original implementation quadratic in the size of the result).
+func ReadVarint(buf []byte) (*BigUInt, int, error) {
+	var acc uint32
+	accBits := uint(0)
+	result := make([]uint8, 0, len(buf)*7/8+1)
+	for i, b := range buf {
+		acc |= uint32(b&0x7F) << accBits
+		accBits += 7
+		for accBits >= 8 {
+			result = append(result, uint8(acc))
+			acc >>= 8
+			accBits -= 8
+		}
+		if b&0x80 == 0 {
+			if accBits > 0 {
+				result = append(result, uint8(acc))
+			}
+			return NewBigUIntFromBytes(result), i + 1, nil
+		}
+	}
+	return nil, 0, ErrVarintOverflow
+}
+
+// WriteVarintTo writes the varint encoding of x to w, returning the
+// number of bytes written.
+func (x *BigUInt) WriteVarintTo(w io.Writer) (int, error) {
+	buf := make([]byte, x.SizeVarint())
+	n, err := x.PutVarint(buf)
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf[:n])
 }
 
-// Bytes provides access to the raw bytes underlying a given BigUInt
+// ReadVarintFrom decodes a BigUInt by reading varint-encoded bytes one at
+// a time from r, returning the number of bytes read. It returns
+// ErrVarintOverflow if the stream does not terminate within
+// maxVarintBytes continuation bytes.
+//
+// Like ReadVarint, this accumulates 7-bit groups into a small bit buffer
+// and flushes whole bytes out of it as they fill up, rather than growing
+// the result one BigUInt.Add per group.
+func ReadVarintFrom(r io.ByteReader) (*BigUInt, int, error) {
+	var acc uint32
+	accBits := uint(0)
+	var result []uint8
+	n := 0
+	for n < maxVarintBytes {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, n, err
+		}
+		n++
+		acc |= uint32(b&0x7F) << accBits
+		accBits += 7
+		for accBits >= 8 {
+			result = append(result, uint8(acc))
+			acc >>= 8
+			accBits -= 8
+		}
+		if b&0x80 == 0 {
+			if accBits > 0 {
+				result = append(result, uint8(acc))
+			}
+			return NewBigUIntFromBytes(result), n, nil
+		}
+	}
+	return nil, n, ErrVarintOverflow
+}
+
+// Bytes provides access to the raw bytes underlying a given BigUInt, by
+// projecting its words into a little-endian byte slice.
 func (x *BigUInt) Bytes() []uint8 {
+	result := make([]uint8, len(x.data)*wordBytes)
+	for i, w := range x.data {
+		for b := 0; b < wordBytes; b++ {
+			result[i*wordBytes+b] = uint8(w >> uint(b*8))
+		}
+	}
+	return trimZeroes(result)
+}
+
+// Words provides access to the raw machine words underlying a given
+// BigUInt, least significant word first.
+func (x *BigUInt) Words() []Word {
 	return x.data
 }
 
@@ -111,15 +565,16 @@ func (x *BigUInt) Bytes() []uint8 {
 // see https://golang.org/pkg/fmt/#Formatter for reference material on
 // golang's printf-style string formatting
 func (x *BigUInt) String() string {
-	if len(x.data) == 0 {
+	data := x.Bytes()
+	if len(data) == 0 {
 		return "0x0"
 	}
 	str := "0x"
-	for i := len(x.data) - 1; i >= 0; i-- {
-		if x.data[i] > 0xF || i == len(x.data)-1 {
-			str += fmt.Sprintf("%x", x.data[i])
+	for i := len(data) - 1; i >= 0; i-- {
+		if data[i] > 0xF || i == len(data)-1 {
+			str += fmt.Sprintf("%x", data[i])
 		} else {
-			str += fmt.Sprintf("0%x", x.data[i])
+			str += fmt.Sprintf("0%x", data[i])
 		}
 		if i != 0 && i%4 == 0 {
 			str += "_"
@@ -131,10 +586,248 @@ func (x *BigUInt) String() string {
 // Copy generates a fully independent (deep) copy of a given BigUInt
 func (x *BigUInt) Copy() *BigUInt {
 	len := len(x.data)
-	ret := make([]uint8, len)
+	ret := make([]Word, len)
 	for i, v := range x.data {
 		ret[i] = v
 	}
 	bigUInt := BigUInt{data: ret}
 	return &bigUInt
 }
+
+// ErrInvalidDigit is returned by FromString/SetString when s contains a
+// byte that is not a valid digit for the requested base, or a misplaced
+// underscore. Offset identifies the offending byte within s.
+type ErrInvalidDigit struct {
+	Offset int
+	Char   byte
+}
+
+func (e *ErrInvalidDigit) Error() string {
+	if e.Char == 0 {
+		return fmt.Sprintf("biguint: no digits found at offset %d", e.Offset)
+	}
+	return fmt.Sprintf("biguint: invalid digit %q at offset %d", e.Char, e.Offset)
+}
+
+// FromString parses s as an unsigned integer in the given base and
+// returns the resulting BigUInt. See SetString for the accepted syntax.
+func FromString(s string, base int) (*BigUInt, error) {
+	x := NewBigUInt(0)
+	if err := x.SetString(s, base); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// SetString parses s as an unsigned integer in the given base, and on
+// success replaces x's value with the result.
+//
+// If base is 0, the base is detected from the prefix of s, following the
+// convention used by math/big.Int.SetString: "0x"/"0X" selects base 16,
+// "0b"/"0B" selects base 2, "0o"/"0O" or a lone leading "0" selects base
+// 8, and anything else is base 10. Underscores between digits are
+// accepted and ignored, so that SetString can parse the strings produced
+// by String.
+//
+// On a malformed input, (*ErrInvalidDigit) is returned identifying the
+// offending byte, and x is left unchanged.
+func (x *BigUInt) SetString(s string, base int) error {
+	digits, base, offset := s, base, 0
+	if base == 0 {
+		switch {
+		case len(digits) >= 2 && digits[0] == '0' && (digits[1] == 'x' || digits[1] == 'X'):
+			base, digits, offset = 16, digits[2:], 2
+		case len(digits) >= 2 && digits[0] == '0' && (digits[1] == 'b' || digits[1] == 'B'):
+			base, digits, offset = 2, digits[2:], 2
+		case len(digits) >= 2 && digits[0] == '0' && (digits[1] == 'o' || digits[1] == 'O'):
+			base, digits, offset = 8, digits[2:], 2
+		case len(digits) >= 2 && digits[0] == '0':
+			base, digits, offset = 8, digits[1:], 1
+		default:
+			base = 10
+		}
+	}
+
+	digitVals := make([]int, 0, len(digits))
+	sawDigit, prevUnderscore := false, false
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		if c == '_' {
+			if !sawDigit || prevUnderscore || i == len(digits)-1 {
+				return &ErrInvalidDigit{Offset: offset + i, Char: c}
+			}
+			prevUnderscore = true
+			continue
+		}
+		v, ok := digitValue(c)
+		if !ok || v >= base {
+			return &ErrInvalidDigit{Offset: offset + i, Char: c}
+		}
+		digitVals = append(digitVals, v)
+		sawDigit, prevUnderscore = true, false
+	}
+	if !sawDigit {
+		return &ErrInvalidDigit{Offset: offset + len(digits)}
+	}
+
+	if base&(base-1) == 0 {
+		x.data = wordsFromBytes(packPowerOfTwoDigits(digitVals, base))
+		return nil
+	}
+
+	acc := NewBigUInt(0)
+	baseValue := NewBigUInt(uint64(base))
+	for _, v := range digitVals {
+		acc = acc.Multiply(baseValue).Add(NewBigUInt(uint64(v)))
+	}
+	x.data = acc.data
+	return nil
+}
+
+// digitValue returns the numeric value of a digit character (using
+// a-z/A-Z for digits beyond 9), and whether c is a digit character at
+// all. It does not check the value against a particular base.
+func digitValue(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 10, true
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10, true
+	default:
+		return 0, false
+	}
+}
+
+// packPowerOfTwoDigits packs most-significant-first digit values, each
+// valid in the given power-of-two base, directly into a little-endian
+// byte slice, without going through Multiply/Add.
+func packPowerOfTwoDigits(digitVals []int, base int) []uint8 {
+	bitsPerDigit := 0
+	for b := base; b > 1; b >>= 1 {
+		bitsPerDigit++
+	}
+	totalBits := len(digitVals) * bitsPerDigit
+	result := make([]uint8, (totalBits+7)/8)
+	bitPos := 0
+	for i := len(digitVals) - 1; i >= 0; i-- {
+		v := digitVals[i]
+		for b := 0; b < bitsPerDigit; b++ {
+			if v&(1<<uint(b)) != 0 {
+				result[bitPos/8] |= 1 << uint(bitPos%8)
+			}
+			bitPos++
+		}
+	}
+	return trimZeroes(result)
+}
+
+// BytesLittleEndian returns the little-endian byte representation of x.
+// It is equivalent to Bytes, which predates this method and is kept
+// as-is for backward compatibility; BytesLittleEndian exists so that
+// callers can make the byte order explicit at the call site.
+func (x *BigUInt) BytesLittleEndian() []uint8 {
+	return x.Bytes()
+}
+
+// BytesBigEndian returns the big-endian byte representation of x, with
+// no leading zero bytes.
+func (x *BigUInt) BytesBigEndian() []uint8 {
+	data := x.Bytes()
+	result := make([]uint8, len(data))
+	for i, b := range data {
+		result[len(data)-1-i] = b
+	}
+	return result
+}
+
+// FillBytesLittleEndian sets dst to the little-endian byte representation
+// of x, zero-padding the most significant (i.e. high-index) end of dst.
+// It returns io.ErrShortBuffer, leaving dst unchanged, if dst is not
+// large enough to hold x's significant bytes.
+func (x *BigUInt) FillBytesLittleEndian(dst []byte) error {
+	data := x.Bytes()
+	if len(data) > len(dst) {
+		return io.ErrShortBuffer
+	}
+	for i := range dst {
+		dst[i] = 0
+	}
+	copy(dst, data)
+	return nil
+}
+
+// FillBytesBigEndian sets dst to the big-endian byte representation of
+// x, zero-padding the most significant (i.e. low-index) end of dst. It
+// returns io.ErrShortBuffer, leaving dst unchanged, if dst is not large
+// enough to hold x's significant bytes.
+func (x *BigUInt) FillBytesBigEndian(dst []byte) error {
+	data := x.Bytes()
+	if len(data) > len(dst) {
+		return io.ErrShortBuffer
+	}
+	for i := range dst {
+		dst[i] = 0
+	}
+	for i, b := range data {
+		dst[len(dst)-1-i] = b
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The format is a
+// varint-encoded length prefix (see PutVarint) followed by that many
+// little-endian bytes (see Bytes).
+func (x *BigUInt) MarshalBinary() ([]byte, error) {
+	data := x.Bytes()
+	length := NewBigUInt(uint64(len(data)))
+	buf := make([]byte, length.SizeVarint())
+	n, err := length.PutVarint(buf)
+	if err != nil {
+		return nil, err
+	}
+	return append(buf[:n], data...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, reading the
+// format written by MarshalBinary. It returns io.ErrUnexpectedEOF if
+// data is truncated before the length prefix says it should end.
+func (x *BigUInt) UnmarshalBinary(data []byte) error {
+	length, n, err := ReadVarint(data)
+	if err != nil {
+		return err
+	}
+	byteLen := int(uint64Value(length))
+	if n+byteLen > len(data) {
+		return io.ErrUnexpectedEOF
+	}
+	x.data = wordsFromBytes(data[n : n+byteLen])
+	return nil
+}
+
+// uint64Value returns the low 64 bits of x, truncating any higher bits.
+// It is only used internally to read back small values (e.g. the length
+// prefix in MarshalBinary) that are known not to need more than 64 bits.
+func uint64Value(x *BigUInt) uint64 {
+	var v uint64
+	for i, b := range x.Bytes() {
+		if i >= 8 {
+			break
+		}
+		v |= uint64(b) << uint(8*i)
+	}
+	return v
+}
+
+// MarshalText implements encoding.TextMarshaler, using the same format
+// as String.
+func (x *BigUInt) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the format
+// produced by MarshalText/String via SetString with base 0.
+func (x *BigUInt) UnmarshalText(text []byte) error {
+	return x.SetString(string(text), 0)
+}
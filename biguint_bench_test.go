@@ -0,0 +1,57 @@
+package biguint
+
+import "testing"
+
+// addBytesForBenchmark re-implements the byte-wise Add this package used
+// before BigUInt switched to a []Word representation, kept only so that
+// BenchmarkAddByteWise has something to compare BenchmarkAddWordWise
+// against.
+func addBytesForBenchmark(x, y []uint8) []uint8 {
+	result := make([]uint8, len(x))
+	copy(result, x)
+	var carry uint16
+	for i := range result {
+		sum := uint16(result[i]) + carry
+		if i < len(y) {
+			sum += uint16(y[i])
+		}
+		result[i] = uint8(sum % 256)
+		carry = sum / 256
+	}
+	if carry > 0 {
+		result = append(result, uint8(carry))
+	}
+	return result
+}
+
+func benchmarkAddByteWise(b *testing.B, size int) {
+	x := make([]uint8, size)
+	y := make([]uint8, size)
+	for i := range x {
+		x[i] = uint8(i)
+		y[i] = uint8(size - i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		addBytesForBenchmark(x, y)
+	}
+}
+
+func benchmarkAddWordWise(b *testing.B, size int) {
+	data := make([]uint8, size)
+	for i := range data {
+		data[i] = uint8(i)
+	}
+	x := NewBigUIntFromBytes(data)
+	y := NewBigUIntFromBytes(data)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Copy().Add(y)
+	}
+}
+
+func BenchmarkAddByteWise1KB(b *testing.B) { benchmarkAddByteWise(b, 1<<10) }
+func BenchmarkAddByteWise1MB(b *testing.B) { benchmarkAddByteWise(b, 1<<20) }
+
+func BenchmarkAddWordWise1KB(b *testing.B) { benchmarkAddWordWise(b, 1<<10) }
+func BenchmarkAddWordWise1MB(b *testing.B) { benchmarkAddWordWise(b, 1<<20) }
@@ -3,7 +3,10 @@
 package biguint
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
 	"reflect"
 	"testing"
@@ -92,12 +95,11 @@ func TestCopy(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf("0x%x", test.input), func(t *testing.T) {
-			var source BigUInt
-			source.data = test.input
+			source := NewBigUIntFromBytes(test.input)
 			dest := source.Copy()
 
-			if len(dest.data) != len(source.data) {
-				t.Fatalf("Copy Failed, copied bytes: %d; Expected: %d ", len(dest.data), len(source.data))
+			if len(dest.Words()) != len(source.Words()) {
+				t.Fatalf("Copy Failed, copied words: %d; Expected: %d ", len(dest.Words()), len(source.Words()))
 			}
 
 			if source.String() != dest.String() {
@@ -108,7 +110,7 @@ func TestCopy(t *testing.T) {
 			* Check if both piont to the same slice object
 			* Now modify dest, and check if source and dest still match
 			 */
-			rindex := rand.Intn(len(dest.data))
+			rindex := rand.Intn(len(dest.Words()))
 			dest.data[rindex]++
 
 			if source.data[rindex] == dest.data[rindex] {
@@ -193,3 +195,394 @@ func TestSubtract(t *testing.T) {
 		})
 	}
 }
+
+func TestMultiply(t *testing.T) {
+	type Test struct {
+		lhs      uint64
+		rhs      uint64
+		expected string
+	}
+	tests := []Test{
+		{0x0, 0x0, "0x0"},
+		{0x0, 0xff, "0x0"},
+		{0x2, 0x3, "0x6"},
+		{0xff, 0x2, "0x1fe"},
+		{0xff, 0xff, "0xfe01"},
+		{0x100000ff, 0x100000ff, "0x100001f_e000fe01"},
+		{0xffffffff_ffffffff, 0x2, "0x1_ffffffff_fffffffe"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("0x%x * 0x%x", test.lhs, test.rhs), func(t *testing.T) {
+			result := NewBigUInt(test.lhs).Multiply(NewBigUInt(test.rhs))
+			resultStr := result.String()
+			if test.expected != resultStr {
+				t.Fatalf("%s, %s does not equal expected value %s", resultStr, prettyPrintUInt8Slice(result.Bytes()), test.expected)
+			}
+		})
+	}
+}
+
+func TestDivMod(t *testing.T) {
+	type Test struct {
+		lhs          uint64
+		rhs          uint64
+		expectedQuot string
+		expectedRem  string
+		errExpected  error
+	}
+	tests := []Test{
+		{0x6, 0x3, "0x2", "0x0", nil},
+		{0x7, 0x3, "0x2", "0x1", nil},
+		{0x0, 0x5, "0x0", "0x0", nil},
+		{0x5, 0x5, "0x1", "0x0", nil},
+		{0x3, 0x7, "0x0", "0x3", nil},
+		{0xffffffff_ffffffff, 0x100, "0xffffff_ffffffff", "0xff", nil},
+		{0xffffffff_ffffffff, 0xffffffff_ffffffff, "0x1", "0x0", nil},
+		{0x1, 0x0, "", "", ErrDivideByZero},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("0x%x / 0x%x", test.lhs, test.rhs), func(t *testing.T) {
+			quot, rem, err := NewBigUInt(test.lhs).DivMod(NewBigUInt(test.rhs))
+			if test.errExpected != nil {
+				if test.errExpected != err {
+					t.Fatalf("Expected error %v, got %v", test.errExpected, err)
+				}
+				if quot != nil || rem != nil {
+					t.Fatalf("Expected nil results in error case, got quot=%v, rem=%v", quot, rem)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if test.expectedQuot != quot.String() {
+				t.Fatalf("quotient %s does not equal expected value %s", quot.String(), test.expectedQuot)
+			}
+			if test.expectedRem != rem.String() {
+				t.Fatalf("remainder %s does not equal expected value %s", rem.String(), test.expectedRem)
+			}
+		})
+	}
+}
+
+func TestDivide(t *testing.T) {
+	result, err := NewBigUInt(0xff).Divide(NewBigUInt(0x10))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.String() != "0xf" {
+		t.Fatalf("%s does not equal expected value 0xf", result.String())
+	}
+
+	if _, err := NewBigUInt(0x1).Divide(NewBigUInt(0x0)); err != ErrDivideByZero {
+		t.Fatalf("Expected error %v, got %v", ErrDivideByZero, err)
+	}
+}
+
+func TestModulo(t *testing.T) {
+	result, err := NewBigUInt(0xff).Modulo(NewBigUInt(0x10))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.String() != "0xf" {
+		t.Fatalf("%s does not equal expected value 0xf", result.String())
+	}
+
+	if _, err := NewBigUInt(0x1).Modulo(NewBigUInt(0x0)); err != ErrDivideByZero {
+		t.Fatalf("Expected error %v, got %v", ErrDivideByZero, err)
+	}
+}
+
+func TestVarintRoundTrip(t *testing.T) {
+	type Test struct {
+		input    uint64
+		expected []byte
+	}
+	tests := []Test{
+		{0x0, []byte{0x00}},
+		{0x1, []byte{0x01}},
+		{0x7f, []byte{0x7f}},
+		{0x80, []byte{0x80, 0x01}},
+		{0x12345678_87654321, []byte{0xa1, 0x86, 0x95, 0xbb, 0x88, 0xcf, 0x95, 0x9a, 0x12}},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("0x%x", test.input), func(t *testing.T) {
+			x := NewBigUInt(test.input)
+			if size := x.SizeVarint(); size != len(test.expected) {
+				t.Fatalf("SizeVarint() = %d, expected %d", size, len(test.expected))
+			}
+			buf := make([]byte, x.SizeVarint())
+			n, err := x.PutVarint(buf)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(test.expected, buf[:n]) {
+				t.Fatalf("%s does not equal expected value %s", prettyPrintUInt8Slice(buf[:n]), prettyPrintUInt8Slice(test.expected))
+			}
+
+			result, m, err := ReadVarint(test.expected)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if m != len(test.expected) {
+				t.Fatalf("ReadVarint consumed %d bytes, expected %d", m, len(test.expected))
+			}
+			if result.String() != x.String() {
+				t.Fatalf("%s does not equal expected value %s", result.String(), x.String())
+			}
+		})
+	}
+}
+
+func TestPutVarintShortBuffer(t *testing.T) {
+	buf := make([]byte, 1)
+	if _, err := NewBigUInt(0x80).PutVarint(buf); err != io.ErrShortBuffer {
+		t.Fatalf("Expected error %v, got %v", io.ErrShortBuffer, err)
+	}
+}
+
+func TestReadVarintOverflow(t *testing.T) {
+	buf := []byte{0x80, 0x80, 0x80}
+	if _, _, err := ReadVarint(buf); err != ErrVarintOverflow {
+		t.Fatalf("Expected error %v, got %v", ErrVarintOverflow, err)
+	}
+}
+
+func TestVarintWriteReadRoundTrip(t *testing.T) {
+	x := NewBigUInt(0x12345678_87654321)
+	var buf bytes.Buffer
+	n, err := x.WriteVarintTo(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if n != x.SizeVarint() {
+		t.Fatalf("wrote %d bytes, expected %d", n, x.SizeVarint())
+	}
+
+	result, m, err := ReadVarintFrom(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if m != n {
+		t.Fatalf("read %d bytes, expected %d", m, n)
+	}
+	if result.String() != x.String() {
+		t.Fatalf("%s does not equal expected value %s", result.String(), x.String())
+	}
+}
+
+func TestNewBigUIntFromBytes(t *testing.T) {
+	type Test struct {
+		input uint64
+	}
+	tests := []Test{
+		{0x0},
+		{0xff},
+		{0xffffffff_ffffffff},
+		{0x12345678_87654321},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("0x%x", test.input), func(t *testing.T) {
+			want := NewBigUInt(test.input)
+			got := NewBigUIntFromBytes(want.Bytes())
+			if got.String() != want.String() {
+				t.Fatalf("%s does not equal expected value %s", got.String(), want.String())
+			}
+			if len(got.Words()) == 0 && test.input != 0 {
+				t.Fatalf("expected non-empty Words() for nonzero input")
+			}
+		})
+	}
+}
+
+func TestFromString(t *testing.T) {
+	type Test struct {
+		input    string
+		base     int
+		expected string
+	}
+	tests := []Test{
+		{"0", 0, "0x0"},
+		{"0x0", 0, "0x0"},
+		{"123", 0, "0x7b"},
+		{"0x7b", 0, "0x7b"},
+		{"0X7B", 0, "0x7b"},
+		{"0b101", 0, "0x5"},
+		{"0o17", 0, "0xf"},
+		{"017", 0, "0xf"},
+		{"ff", 16, "0xff"},
+		{"18446744073709551616", 0, "0x1_00000000_00000000"},
+		{"0x12345678_87654321", 0, "0x12345678_87654321"},
+		{"12_345", 0, "0x3039"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s base %d", test.input, test.base), func(t *testing.T) {
+			result, err := FromString(test.input, test.base)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.String() != test.expected {
+				t.Fatalf("%s does not equal expected value %s", result.String(), test.expected)
+			}
+		})
+	}
+}
+
+func TestFromStringRoundTrip(t *testing.T) {
+	inputs := []uint64{0x0, 0x1, 0xff, 0x12345678_87654321, 0xffffffff_ffffffff}
+	for _, input := range inputs {
+		x := NewBigUInt(input)
+		t.Run(x.String(), func(t *testing.T) {
+			result, err := FromString(x.String(), 0)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.String() != x.String() {
+				t.Fatalf("%s does not equal expected value %s", result.String(), x.String())
+			}
+		})
+	}
+}
+
+func TestFromStringErrors(t *testing.T) {
+	type Test struct {
+		input        string
+		base         int
+		expectedChar byte
+		expectedOff  int
+	}
+	tests := []Test{
+		{"", 0, 0, 0},
+		{"0x", 0, 0, 2},
+		{"0x_", 0, '_', 2},
+		{"12_", 10, '_', 2},
+		{"_12", 10, '_', 0},
+		{"1__2", 10, '_', 2},
+		{"12g", 16, 'g', 2},
+		{"9", 2, '9', 0},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s base %d", test.input, test.base), func(t *testing.T) {
+			_, err := FromString(test.input, test.base)
+			invalidDigit, ok := err.(*ErrInvalidDigit)
+			if !ok {
+				t.Fatalf("expected *ErrInvalidDigit, got %v", err)
+			}
+			if invalidDigit.Char != test.expectedChar || invalidDigit.Offset != test.expectedOff {
+				t.Fatalf("got {Char: %q, Offset: %d}, expected {Char: %q, Offset: %d}",
+					invalidDigit.Char, invalidDigit.Offset, test.expectedChar, test.expectedOff)
+			}
+		})
+	}
+}
+
+func TestBytesBigEndian(t *testing.T) {
+	type Test struct {
+		input    uint64
+		expected []uint8
+	}
+	tests := []Test{
+		{0x0, []uint8{}},
+		{0x1, []uint8{0x01}},
+		{0x12345678_87654321, []uint8{0x12, 0x34, 0x56, 0x78, 0x87, 0x65, 0x43, 0x21}},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("0x%x", test.input), func(t *testing.T) {
+			x := NewBigUInt(test.input)
+			if !reflect.DeepEqual(test.expected, x.BytesBigEndian()) {
+				t.Fatalf("%s does not equal expected value %s",
+					prettyPrintUInt8Slice(x.BytesBigEndian()), prettyPrintUInt8Slice(test.expected))
+			}
+			if !reflect.DeepEqual(x.Bytes(), x.BytesLittleEndian()) {
+				t.Fatalf("BytesLittleEndian() does not equal Bytes()")
+			}
+		})
+	}
+}
+
+func TestFillBytes(t *testing.T) {
+	x := NewBigUInt(0xff)
+
+	big := make([]byte, 4)
+	if err := x.FillBytesBigEndian(big); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(big, []byte{0x00, 0x00, 0x00, 0xff}) {
+		t.Fatalf("%s does not equal expected value %s", prettyPrintUInt8Slice(big), prettyPrintUInt8Slice([]byte{0x00, 0x00, 0x00, 0xff}))
+	}
+
+	little := make([]byte, 4)
+	if err := x.FillBytesLittleEndian(little); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(little, []byte{0xff, 0x00, 0x00, 0x00}) {
+		t.Fatalf("%s does not equal expected value %s", prettyPrintUInt8Slice(little), prettyPrintUInt8Slice([]byte{0xff, 0x00, 0x00, 0x00}))
+	}
+
+	tooSmall := make([]byte, 0)
+	if err := x.FillBytesBigEndian(tooSmall); err != io.ErrShortBuffer {
+		t.Fatalf("Expected error %v, got %v", io.ErrShortBuffer, err)
+	}
+	if err := x.FillBytesLittleEndian(tooSmall); err != io.ErrShortBuffer {
+		t.Fatalf("Expected error %v, got %v", io.ErrShortBuffer, err)
+	}
+}
+
+func TestMarshalBinaryRoundTrip(t *testing.T) {
+	inputs := []uint64{0x0, 0x1, 0xff, 0x12345678_87654321, 0xffffffff_ffffffff}
+	for _, input := range inputs {
+		x := NewBigUInt(input)
+		t.Run(x.String(), func(t *testing.T) {
+			data, err := x.MarshalBinary()
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			result := NewBigUInt(0)
+			if err := result.UnmarshalBinary(data); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if result.String() != x.String() {
+				t.Fatalf("%s does not equal expected value %s", result.String(), x.String())
+			}
+		})
+	}
+}
+
+func TestUnmarshalBinaryTruncated(t *testing.T) {
+	x := NewBigUInt(0x12345678_87654321)
+	data, err := x.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	result := NewBigUInt(0)
+	if err := result.UnmarshalBinary(data[:len(data)-1]); err != io.ErrUnexpectedEOF {
+		t.Fatalf("Expected error %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	x := NewBigUInt(0x12345678_87654321)
+	data, err := json.Marshal(x)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := `"0x12345678_87654321"`
+	if string(data) != expected {
+		t.Fatalf("%s does not equal expected value %s", data, expected)
+	}
+
+	result := NewBigUInt(0)
+	if err := json.Unmarshal(data, result); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if result.String() != x.String() {
+		t.Fatalf("%s does not equal expected value %s", result.String(), x.String())
+	}
+}